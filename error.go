@@ -26,6 +26,13 @@
 //   - Wrap: Wraps the given error with a new error that includes the provided message.
 //   - Wrapf: Wraps the given error with a new error that includes a formatted message.
 //   - WrapWithCause: Wraps the given error with a new error that includes the cause.
+//   - Cause: Returns the root cause of an error by repeatedly unwrapping it.
+//   - Join: Joins multiple errors into one, capturing a stack trace at the join site.
+//   - NewTyped: Returns a new error tagged with a Kind, for control-flow decisions.
+//   - WrapTyped: Wraps the given error with a new error tagged with a Kind.
+//   - Find: Returns the first error in the chain whose Kind matches the given type.
+//   - FindKind: Reports whether any error in the chain carries the given Kind.
+//   - LogValue: Returns a slog.Value describing an error for structured logging.
 //
 // The package also provides the `withStack` type, which represents an error with
 // an associated stack trace. It implements the `error` interface and provides
@@ -67,6 +74,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 )
 
 var (
@@ -100,7 +108,9 @@ func WithStack(err error) error {
 }
 
 // newWithStack is an internal helper function that creates a new error with a stack trace.
-// If the given error already has a stack trace, it appends the new stack trace to the existing one.
+// If err is itself already a *withStack, its stack is extended in place rather than
+// adding another layer of wrapping. Otherwise, if a *withStack or *joinedError is
+// reachable further down err's chain, its stack is folded into the new one.
 //
 // Parameters:
 //   - err: The error to annotate with a stack trace.
@@ -110,15 +120,67 @@ func WithStack(err error) error {
 //   - A new error annotated with a stack trace.
 func newWithStack(err error, skip int) error {
 	s := caller(skip)
-	var w *withStack
-	if errors.As(err, &w) {
+
+	if w, ok := err.(*withStack); ok {
 		w.stack = appendStackTrace(s, w.stack)
 		return w
 	}
 
+	if j, ok := err.(*joinedError); ok {
+		return &withStack{
+			err:   j,
+			stack: appendStackTrace(s, j.stack),
+		}
+	}
+
+	stack, kind := mergeChildStack(s, err)
+
 	return &withStack{
 		err:   err,
-		stack: s,
+		stack: stack,
+		kind:  kind,
+	}
+}
+
+// nearestStackHolder walks err's Unwrap() error chain, the same single-error
+// chain Cause follows, looking for the nearest *withStack or *joinedError to
+// merge stacks with. It stops rather than recursing once it reaches an
+// Unwrap() []error node, so it never dives into a joined error's children and
+// collapses the aggregation down to whichever one happens to be a *withStack.
+func nearestStackHolder(err error) error {
+	for err != nil {
+		switch x := err.(type) {
+		case *withStack:
+			return x
+		case *joinedError:
+			return x
+		case interface{ Unwrap() []error }:
+			return nil
+		case interface{ Unwrap() error }:
+			err = x.Unwrap()
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// mergeChildStack folds the stack of the nearest *withStack or *joinedError
+// reachable from err into stack, and reports its Kind, if it has one. It
+// follows ordinary Unwrap() error wrapping, such as an intermediate
+// fmt.Errorf("%w", ...), the same as errors.As would, but via
+// nearestStackHolder never descends into an Unwrap() []error node, so a
+// joined error is folded in as a whole instead of being unwrapped into
+// whichever of its children happens to be a *withStack.
+func mergeChildStack(stack StackTrace, err error) (StackTrace, Kind) {
+	switch x := nearestStackHolder(err).(type) {
+	case *withStack:
+		return appendStackTrace(stack, x.stack), x.kind
+	case *joinedError:
+		return appendStackTrace(stack, x.stack), nil
+	default:
+		return stack, nil
 	}
 }
 
@@ -131,16 +193,13 @@ func Wrap(err error, msg string) error {
 	}
 
 	withMsg := fmt.Errorf("%s: %w", msg, err)
-	var w *withStack
 
-	stack := caller(2)
-	if errors.As(err, &w) {
-		stack = appendStackTrace(stack, w.stack)
-	}
+	stack, kind := mergeChildStack(caller(2), err)
 
 	return &withStack{
 		err:   withMsg,
 		stack: stack,
+		kind:  kind,
 	}
 }
 
@@ -153,16 +212,13 @@ func Wrapf(err error, format string, args ...any) error {
 	}
 
 	withMsg := fmt.Errorf("%s: %w", fmt.Sprintf(format, args...), err)
-	var w *withStack
 
-	stack := caller(2)
-	if errors.As(err, &w) {
-		stack = appendStackTrace(stack, w.stack)
-	}
+	stack, kind := mergeChildStack(caller(2), err)
 
 	return &withStack{
 		err:   withMsg,
 		stack: stack,
+		kind:  kind,
 	}
 }
 
@@ -178,19 +234,178 @@ func WrapWithCause(err, cause error) error {
 		return newWithStack(err, 3)
 	}
 	withMsg := fmt.Errorf("%w: %w", err, cause)
-	var errWithStack, causeWithStack *withStack
 
+	stack, kind := mergeChildStack(caller(2), err)
+	stack, _ = mergeChildStack(stack, cause)
+
+	return &withStack{
+		err:   withMsg,
+		stack: stack,
+		kind:  kind,
+	}
+}
+
+// Join returns an error that wraps the given non-nil errors. Nil values in
+// errs are discarded. Join captures a stack trace at the call site.
+//
+// If exactly one non-nil error is given, that error is returned wrapped
+// once with this frame, the same way Wrap would, instead of allocating a
+// *joinedError. If no non-nil error is given, Join returns nil.
+//
+// The returned error's Error method joins the messages of the non-nil
+// errors with a newline, and its Is and As methods examine every wrapped
+// error through Unwrap() []error.
+func Join(errs ...error) error {
 	stack := caller(2)
-	if errors.As(err, &errWithStack) {
-		stack = appendStackTrace(stack, errWithStack.stack)
+
+	nonNil := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if err != nil {
+			nonNil = append(nonNil, err)
+		}
+	}
+	if len(nonNil) == 0 {
+		return nil
+	}
+	if len(nonNil) == 1 {
+		err := nonNil[0]
+		stack, _ = mergeChildStack(stack, err)
+
+		return &withStack{
+			err:   err,
+			stack: stack,
+		}
+	}
+
+	return &joinedError{
+		errs:  nonNil,
+		stack: stack,
 	}
-	if errors.As(cause, &causeWithStack) {
-		stack = appendStackTrace(stack, causeWithStack.stack)
+}
+
+// Causer is implemented by error types that can report the error that
+// caused them. It predates Go 1.13's Unwrap-based error chains and exists
+// so that this package can interoperate with code written against the
+// older github.com/pkg/errors idiom.
+type Causer interface {
+	Cause() error
+}
+
+// Cause returns the root cause of err by repeatedly unwrapping it until it
+// reaches an error that has neither an Unwrap() error nor an
+// Unwrap() []error method. If err is nil, Cause returns nil.
+func Cause(err error) error {
+	for err != nil {
+		switch x := err.(type) {
+		case interface{ Unwrap() []error }:
+			return err
+		case interface{ Unwrap() error }:
+			next := x.Unwrap()
+			if next == nil {
+				return err
+			}
+			err = next
+		default:
+			return err
+		}
 	}
 
+	return err
+}
+
+// Kind identifies the category of an error, e.g. TransientKind or
+// NotFoundKind, so that callers can branch on it without resorting to
+// string matching or sentinel values.
+type Kind interface {
+	ErrorKind() string
+}
+
+// NewTyped returns a new error with the given error message, tagged with
+// kind. The error is annotated with a stack trace starting from the point
+// where this function is called.
+func NewTyped(kind Kind, msg string) error {
+	return &withStack{
+		err:   errors.New(msg),
+		stack: caller(2),
+		kind:  kind,
+	}
+}
+
+// WrapTyped wraps the given error with a new error that includes the
+// provided message, tagged with kind. If the given error is nil, nil is
+// returned. The returned error is annotated with a stack trace starting
+// from the point where this function is called.
+func WrapTyped(err error, kind Kind, msg string) error {
+	if err == nil {
+		return nil
+	}
+
+	withMsg := fmt.Errorf("%s: %w", msg, err)
+
+	stack, _ := mergeChildStack(caller(2), err)
+
 	return &withStack{
 		err:   withMsg,
 		stack: stack,
+		kind:  kind,
+	}
+}
+
+// Find walks the error chain of err, including the children of any
+// Unwrap() []error node, and returns the first kind assignable to T.
+// It reports whether a match was found.
+func Find[T Kind](err error) (T, bool) {
+	var found T
+	ok := false
+	walkKinds(err, func(k Kind) bool {
+		if t, match := k.(T); match {
+			found = t
+			ok = true
+			return false
+		}
+		return true
+	})
+
+	return found, ok
+}
+
+// FindKind reports whether any error in the chain of err, including the
+// children of any Unwrap() []error node, carries the given kind.
+func FindKind(err error, target Kind) bool {
+	found := false
+	walkKinds(err, func(k Kind) bool {
+		if k == target {
+			found = true
+			return false
+		}
+		return true
+	})
+
+	return found
+}
+
+// walkKinds walks the error chain of err, including the children of any
+// Unwrap() []error node, invoking visit with the kind of every *withStack
+// it encounters that has one. It stops early if visit returns false.
+func walkKinds(err error, visit func(Kind) bool) {
+	for err != nil {
+		if w, ok := err.(*withStack); ok && w.kind != nil {
+			if !visit(w.kind) {
+				return
+			}
+		}
+
+		switch x := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, e := range x.Unwrap() {
+				walkKinds(e, visit)
+			}
+			return
+		case interface{ Unwrap() error }:
+			err = x.Unwrap()
+		default:
+			return
+		}
 	}
 }
 
@@ -199,6 +414,13 @@ func WrapWithCause(err, cause error) error {
 type withStack struct {
 	err   error
 	stack StackTrace
+	kind  Kind
+}
+
+// Cause returns the error wrapped by the withStack struct.
+// It implements the Causer interface.
+func (w *withStack) Cause() error {
+	return w.err
 }
 
 // Is reports whether any error in the error chain matches the target error.
@@ -221,9 +443,9 @@ func (w *withStack) Error() string {
 
 // Format formats the withStack struct according to the fmt.State and verb.
 // It supports the following formatting verbs:
-//   - 'v': If the '+' flag is set in the fmt.State, it writes the error message
-//     followed by the formatted stack trace. If the '+' flag is not set,
-//     it falls through to the 's' verb behavior.
+//   - 'v': If the '+' flag is set in the fmt.State, it writes the error message,
+//     the kind (if any) on its own line, and the formatted stack trace.
+//     If the '+' flag is not set, it falls through to the 's' verb behavior.
 //   - 's': Writes the error message.
 //   - 'q': Writes the quoted error message.
 func (w *withStack) Format(s fmt.State, verb rune) {
@@ -231,6 +453,9 @@ func (w *withStack) Format(s fmt.State, verb rune) {
 	case 'v':
 		if s.Flag('+') {
 			io.WriteString(s, w.Error())
+			if w.kind != nil {
+				fmt.Fprintf(s, "\n[kind=%s]", w.kind.ErrorKind())
+			}
 			w.stack.Format(s, verb)
 			return
 		}
@@ -241,3 +466,54 @@ func (w *withStack) Format(s fmt.State, verb rune) {
 		fmt.Fprintf(s, "%q", w.Error())
 	}
 }
+
+// joinedError is a struct that represents multiple errors joined together,
+// as produced by Join. It implements the error interface and provides
+// additional methods for error handling.
+type joinedError struct {
+	errs  []error
+	stack StackTrace
+}
+
+// Error returns the concatenation of the messages of the joined errors,
+// each on its own line.
+func (j *joinedError) Error() string {
+	var b strings.Builder
+	for i, err := range j.errs {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(err.Error())
+	}
+
+	return b.String()
+}
+
+// Unwrap returns the errors joined by the joinedError struct.
+// It allows errors.Is and errors.As to examine each joined error in turn.
+func (j *joinedError) Unwrap() []error {
+	return j.errs
+}
+
+// Format formats the joinedError struct according to the fmt.State and verb.
+// Under '+v' it writes each joined error's own %+v representation, message
+// followed by stack trace, separated by a divider line.
+func (j *joinedError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			for i, err := range j.errs {
+				if i > 0 {
+					io.WriteString(s, "\n---\n")
+				}
+				fmt.Fprintf(s, "%+v", err)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, j.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", j.Error())
+	}
+}
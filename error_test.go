@@ -1,6 +1,7 @@
 package errors
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"testing"
@@ -62,6 +63,16 @@ func TestWrap(t *testing.T) {
 		assert.ErrorIs(t, got, io.EOF)
 		assert.EqualError(t, got, fmt.Sprintf("failed to execute query: %v", io.EOF))
 	})
+
+	t.Run("stack behind an intermediate wrapper is not lost", func(t *testing.T) {
+		inner := New("inner error")
+		mid := fmt.Errorf("mid: %w", inner)
+		got := Wrap(mid, "outer")
+
+		var w *withStack
+		assert.True(t, errors.As(got, &w))
+		assert.True(t, len(w.stack) >= 2)
+	})
 }
 
 func TestWrapf(t *testing.T) {
@@ -75,6 +86,7 @@ func TestWrapf(t *testing.T) {
 		assert.ErrorIs(t, got, io.EOF)
 		assert.EqualError(t, got, fmt.Sprintf("failed to execute query: %v: %v", "test", io.EOF))
 	})
+
 }
 
 func TestWrapWithCause(t *testing.T) {
@@ -97,6 +109,142 @@ func TestWrapWithCause(t *testing.T) {
 		assert.ErrorIs(t, got, cause)
 		assert.EqualError(t, got, fmt.Sprintf("%v: %v", err, cause))
 	})
+
+}
+
+func TestJoin(t *testing.T) {
+	t.Run("no errors", func(t *testing.T) {
+		assert.Nil(t, Join())
+		assert.Nil(t, Join(nil, nil))
+	})
+
+	t.Run("single error", func(t *testing.T) {
+		got := Join(io.EOF)
+		assert.ErrorIs(t, got, io.EOF)
+		assert.EqualError(t, got, io.EOF.Error())
+	})
+
+	t.Run("multiple errors", func(t *testing.T) {
+		err1 := New("first error")
+		err2 := New("second error")
+		got := Join(nil, err1, err2)
+		assert.ErrorIs(t, got, err1)
+		assert.ErrorIs(t, got, err2)
+		assert.EqualError(t, got, fmt.Sprintf("%s\n%s", err1, err2))
+
+		var joined interface{ Unwrap() []error }
+		assert.True(t, errors.As(got, &joined))
+		assert.Len(t, joined.Unwrap(), 2)
+	})
+}
+
+type transientKind struct{}
+
+func (transientKind) ErrorKind() string { return "transient" }
+
+type notFoundKind struct{}
+
+func (notFoundKind) ErrorKind() string { return "not_found" }
+
+func TestNewTyped(t *testing.T) {
+	got := NewTyped(transientKind{}, "connection reset")
+	assert.EqualError(t, got, "connection reset")
+	kind, ok := Find[transientKind](got)
+	assert.True(t, ok)
+	assert.Equal(t, transientKind{}, kind)
+}
+
+func TestWrapTyped(t *testing.T) {
+	t.Run("nil err", func(t *testing.T) {
+		got := WrapTyped(nil, transientKind{}, "failed to execute query")
+		assert.Nil(t, got)
+	})
+
+	t.Run("OK", func(t *testing.T) {
+		got := WrapTyped(io.EOF, transientKind{}, "failed to execute query")
+		assert.ErrorIs(t, got, io.EOF)
+		assert.EqualError(t, got, fmt.Sprintf("failed to execute query: %v", io.EOF))
+		assert.True(t, FindKind(got, transientKind{}))
+		assert.False(t, FindKind(got, notFoundKind{}))
+	})
+
+	t.Run("kind survives further wrapping", func(t *testing.T) {
+		typed := WrapTyped(io.EOF, transientKind{}, "failed to execute query")
+		got := Wrap(typed, "failed to run transaction")
+		assert.True(t, FindKind(got, transientKind{}))
+	})
+
+}
+
+// TestJoinedErrorNotCollapsed covers every wrapping constructor that merges
+// a child error's stack (see mergeChildStack), checking each one preserves
+// a *joinedError as a whole rather than collapsing it down to one member.
+func TestJoinedErrorNotCollapsed(t *testing.T) {
+	err1 := New("first error")
+	err2 := New("second error")
+	joined := Join(err1, err2)
+
+	tests := []struct {
+		name string
+		got  error
+	}{
+		{"WithStack", WithStack(joined)},
+		{"Wrap", Wrap(joined, "ctx")},
+		{"Wrapf", Wrapf(joined, "ctx %d", 1)},
+		{"WrapWithCause", WrapWithCause(New("err"), joined)},
+		{"WrapTyped", WrapTyped(joined, transientKind{}, "ctx")},
+		{"Join", Join(joined)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.got, err1)
+			assert.ErrorIs(t, tt.got, err2)
+
+			var members interface{ Unwrap() []error }
+			assert.True(t, errors.As(tt.got, &members))
+			assert.Len(t, members.Unwrap(), 2)
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	t.Run("no match", func(t *testing.T) {
+		_, ok := Find[transientKind](New("plain error"))
+		assert.False(t, ok)
+	})
+
+	t.Run("matches through a joined error", func(t *testing.T) {
+		joined := Join(New("plain error"), NewTyped(transientKind{}, "reset"))
+		kind, ok := Find[transientKind](joined)
+		assert.True(t, ok)
+		assert.Equal(t, transientKind{}, kind)
+	})
+}
+
+func TestCause(t *testing.T) {
+	t.Run("nil err", func(t *testing.T) {
+		got := Cause(nil)
+		assert.Nil(t, got)
+	})
+
+	t.Run("OK", func(t *testing.T) {
+		root := io.EOF
+		err := Wrap(root, "failed to execute query")
+		err = Wrap(err, "failed to run transaction")
+		assert.Equal(t, root, Cause(err))
+	})
+
+	t.Run("no stack", func(t *testing.T) {
+		assert.Equal(t, io.EOF, Cause(io.EOF))
+	})
+}
+
+func Test_withStack_Cause(t *testing.T) {
+	root := io.EOF
+	err := Wrap(root, "failed to execute query")
+	var c Causer
+	assert.True(t, errors.As(err, &c))
+	assert.Equal(t, err.(interface{ Unwrap() error }).Unwrap(), c.Cause())
 }
 
 func Test_withStack_Format(t *testing.T) {
@@ -133,8 +281,8 @@ func Test_withStack_Format(t *testing.T) {
 			},
 			format: "%+v",
 			want: "test" +
-				"\n\tat github.com/psi59/errors.X.val(stack_test.go:17)" +
-				"\n\tat github.com/psi59/errors.(*X).ptr(stack_test.go:22)",
+				"\n\tat github.com/psi59/errors.X.val(stack_test.go:19)" +
+				"\n\tat github.com/psi59/errors.(*X).ptr(stack_test.go:24)",
 		},
 		{
 			err: fmt.Errorf("test"),
@@ -144,8 +292,8 @@ func Test_withStack_Format(t *testing.T) {
 			},
 			format: "%+v",
 			want: "test" +
-				"\n\tat github.com/psi59/errors.init(stack_test.go:11)" +
-				"\n\tat github.com/psi59/errors.X.val(stack_test.go:17)",
+				"\n\tat github.com/psi59/errors.init(stack_test.go:13)" +
+				"\n\tat github.com/psi59/errors.X.val(stack_test.go:19)",
 		},
 	}
 	for _, tt := range tests {
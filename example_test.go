@@ -26,9 +26,17 @@ func ExampleWithStack() {
 	err = errors.WithStack(err)
 	fmt.Printf("%+v", err)
 	// Output:
-	//example error
-	//	at github.com/psi59/errors_test.ExampleWithStack(example_test.go:22)
-	//	at github.com/psi59/errors_test.ExampleWithStack(example_test.go:21)
+	// example error
+	//	at github.com/psi59/errors_test.ExampleWithStack(example_test.go:26)
+	//	at github.com/psi59/errors_test.ExampleWithStack(example_test.go:25)
+}
+
+func ExampleWithStack_sameLine() {
+	err := errors.WithStack(errors.New("example error"))
+	fmt.Printf("%+v", err)
+	// Output:
+	// example error
+	//	at github.com/psi59/errors_test.ExampleWithStack_sameLine(example_test.go:35)
 }
 
 func ExampleWrap() {
@@ -37,8 +45,8 @@ func ExampleWrap() {
 	fmt.Printf("%+v", err)
 	// Output:
 	// wrapped: example error
-	//	at github.com/psi59/errors_test.ExampleWrap(example_test.go:36)
-	//	at github.com/psi59/errors_test.ExampleWrap(example_test.go:35)
+	//	at github.com/psi59/errors_test.ExampleWrap(example_test.go:44)
+	//	at github.com/psi59/errors_test.ExampleWrap(example_test.go:43)
 }
 
 func ExampleWrapf() {
@@ -47,8 +55,8 @@ func ExampleWrapf() {
 	fmt.Printf("%+v", err)
 	// Output:
 	// wrapped 123: example error
-	//	at github.com/psi59/errors_test.ExampleWrapf(example_test.go:46)
-	//	at github.com/psi59/errors_test.ExampleWrapf(example_test.go:45)
+	//	at github.com/psi59/errors_test.ExampleWrapf(example_test.go:54)
+	//	at github.com/psi59/errors_test.ExampleWrapf(example_test.go:53)
 }
 
 func ExampleWrapWithCause() {
@@ -58,7 +66,7 @@ func ExampleWrapWithCause() {
 	fmt.Printf("%+v", err)
 	// Output:
 	// example error: cause error
-	//	at github.com/psi59/errors_test.ExampleWrapWithCause(example_test.go:57)
-	//	at github.com/psi59/errors_test.ExampleWrapWithCause(example_test.go:56)
-	//	at github.com/psi59/errors_test.ExampleWrapWithCause(example_test.go:55)
+	//	at github.com/psi59/errors_test.ExampleWrapWithCause(example_test.go:65)
+	//	at github.com/psi59/errors_test.ExampleWrapWithCause(example_test.go:64)
+	//	at github.com/psi59/errors_test.ExampleWrapWithCause(example_test.go:63)
 }
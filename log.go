@@ -0,0 +1,109 @@
+package errors
+
+import (
+	"encoding/json"
+	"log/slog"
+)
+
+// errorJSON is the structured representation shared by withStack's
+// MarshalJSON and LogValue. Cause holds a single wrapped error; Causes
+// holds the branches of a multi-error node such as the one produced by
+// WrapWithCause or Join.
+type errorJSON struct {
+	Message string       `json:"message"`
+	Cause   *errorJSON   `json:"cause,omitempty"`
+	Causes  []*errorJSON `json:"causes,omitempty"`
+	Stack   StackTrace   `json:"stack,omitempty"`
+}
+
+// marshalError builds the structured representation of err, recursing into
+// its wrapped causes. For a *withStack or *joinedError, the stack captured
+// at that point is attached alongside the message.
+func marshalError(err error) *errorJSON {
+	if err == nil {
+		return nil
+	}
+
+	ej := &errorJSON{Message: err.Error()}
+
+	switch x := err.(type) {
+	case *withStack:
+		ej.Stack = x.stack
+		ej.setCause(x.err)
+	case *joinedError:
+		ej.Stack = x.stack
+		for _, e := range x.errs {
+			ej.Causes = append(ej.Causes, marshalError(e))
+		}
+	default:
+		ej.setCause(err)
+	}
+
+	return ej
+}
+
+// setCause inspects err's own Unwrap method (not err itself) and attaches
+// the result as either Cause or Causes, depending on whether it unwraps to
+// one error or many.
+func (ej *errorJSON) setCause(err error) {
+	switch x := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, e := range x.Unwrap() {
+			ej.Causes = append(ej.Causes, marshalError(e))
+		}
+	case interface{ Unwrap() error }:
+		if next := x.Unwrap(); next != nil {
+			ej.Cause = marshalError(next)
+		}
+	}
+}
+
+// MarshalJSON marshals the withStack struct into a stable schema of
+// {"message", "cause", "stack"}, recursing into chained causes (including
+// both branches of a WrapWithCause error). The stack is omitted when empty.
+func (w *withStack) MarshalJSON() ([]byte, error) {
+	return json.Marshal(marshalError(w))
+}
+
+// MarshalJSON marshals the joinedError struct the same way withStack does,
+// with each joined error attached under "causes".
+func (j *joinedError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(marshalError(j))
+}
+
+// LogValue returns a slog.Value describing err's message, stack, and any
+// chained causes, so it can be attached to a log/slog record without
+// writing a custom marshaler.
+func LogValue(err error) slog.Value {
+	ej := marshalError(err)
+	if ej == nil {
+		return slog.Value{}
+	}
+
+	return ej.logValue()
+}
+
+func (ej *errorJSON) logValue() slog.Value {
+	attrs := make([]slog.Attr, 0, 4)
+	attrs = append(attrs, slog.String("message", ej.Message))
+
+	if ej.Cause != nil {
+		attrs = append(attrs, slog.Attr{Key: "cause", Value: ej.Cause.logValue()})
+	}
+	if len(ej.Causes) > 0 {
+		causes := make([]any, len(ej.Causes))
+		for i, c := range ej.Causes {
+			causes[i] = c.logValue()
+		}
+		attrs = append(attrs, slog.Any("causes", causes))
+	}
+	if len(ej.Stack) > 0 {
+		frames := make([]any, len(ej.Stack))
+		for i, f := range ej.Stack {
+			frames[i] = f.String()
+		}
+		attrs = append(attrs, slog.Any("stack", frames))
+	}
+
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,59 @@
+package errors
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_withStack_MarshalJSON(t *testing.T) {
+	t.Run("single cause", func(t *testing.T) {
+		got := Wrap(io.EOF, "failed to execute query")
+		b, err := json.Marshal(got)
+		assert.NoError(t, err)
+
+		var ej errorJSON
+		assert.NoError(t, json.Unmarshal(b, &ej))
+		assert.Equal(t, got.Error(), ej.Message)
+		assert.NotEmpty(t, ej.Stack)
+		assert.Equal(t, io.EOF.Error(), ej.Cause.Message)
+	})
+
+	t.Run("WrapWithCause has both branches", func(t *testing.T) {
+		err := Wrap(io.EOF, "failed to execute query")
+		cause := Errorf("cause")
+		got := WrapWithCause(err, cause)
+
+		b, marshalErr := json.Marshal(got)
+		assert.NoError(t, marshalErr)
+
+		var ej errorJSON
+		assert.NoError(t, json.Unmarshal(b, &ej))
+		assert.Len(t, ej.Causes, 2)
+	})
+}
+
+func Test_joinedError_MarshalJSON(t *testing.T) {
+	got := Join(New("first error"), New("second error"))
+	b, err := json.Marshal(got)
+	assert.NoError(t, err)
+
+	var ej errorJSON
+	assert.NoError(t, json.Unmarshal(b, &ej))
+	assert.Len(t, ej.Causes, 2)
+}
+
+func TestLogValue(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		v := LogValue(nil)
+		assert.True(t, v.Equal(v))
+	})
+
+	t.Run("OK", func(t *testing.T) {
+		got := Wrap(io.EOF, "failed to execute query")
+		v := LogValue(got)
+		assert.Equal(t, got.Error(), v.Group()[0].Value.String())
+	})
+}
@@ -1,11 +1,13 @@
 package errors
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"runtime"
 	"strings"
+	"sync"
 )
 
 var workingDir string
@@ -15,52 +17,246 @@ func init() {
 	workingDir = s
 }
 
+// MaxStackDepth bounds the total number of Frames StackTrace.Frames will
+// expand a StackTrace into, across every program counter it captured, not
+// per program counter. A program counter normally resolves to one Frame,
+// but the compiler can inline several calls into the same address, in
+// which case it resolves to one Frame per inlined call, so a StackTrace
+// with few captured program counters can still reach this cap. It
+// defaults to 32 and is capped at hardStackDepthCap.
+var MaxStackDepth = 32
+
+// hardStackDepthCap is the upper bound enforced on MaxStackDepth, guarding
+// against a misconfigured depth causing an unbounded expansion.
+const hardStackDepthCap = 128
+
+func maxStackDepth() int {
+	if MaxStackDepth <= 0 || MaxStackDepth > hardStackDepthCap {
+		return hardStackDepthCap
+	}
+
+	return MaxStackDepth
+}
+
 // StackTrace represents a stack trace, which is a collection of Frames.
 // It provides methods for formatting and appending stack traces.
 type StackTrace []Frame
 
 // Format formats the StackTrace according to the fmt.State and verb.
-// It iterates over each Frame in the StackTrace and calls its Format method.
+// It resolves s into its Frames and calls each one's Format method.
 func (s StackTrace) Format(state fmt.State, verb rune) {
 	if verb == 'v' {
 		if state.Flag('+') {
-			for _, f := range s {
+			for _, f := range s.Frames() {
 				f.Format(state, verb)
 			}
 		}
 	}
 }
 
-func appendStackTrace(s, ss StackTrace) StackTrace {
-	appended := make([]Frame, len(s)+len(ss))
-	for i, frame := range s {
-		appended[i] = frame
+// Frames resolves every program counter in s into its Frame(s). Unlike
+// Frame.String, which resolves a program counter with runtime.FuncForPC
+// and only sees the outermost function, Frames uses runtime.CallersFrames,
+// so a program counter that the compiler inlined several calls into
+// expands into one Frame per inlined call. Each element of s memoizes its
+// own expansion (see Frame.expand), so calling Frames repeatedly on the
+// same StackTrace, as %+v formatting and JSON marshaling both do, resolves
+// every program counter at most once. The result is capped at
+// MaxStackDepth frames in total, across every element of s.
+func (s StackTrace) Frames() []Frame {
+	if len(s) == 0 {
+		return nil
+	}
+
+	depth := maxStackDepth()
+	frames := make([]Frame, 0, len(s))
+	for i := range s {
+		for _, f := range s[i].expand() {
+			if len(frames) >= depth {
+				return frames
+			}
+			frames = append(frames, f)
+		}
+	}
+
+	return frames
+}
+
+// Merge concatenates s with other, dropping the longest suffix of s that
+// duplicates a prefix of other. This keeps a single entry for a call site
+// that would otherwise be recorded twice, which happens when an annotating
+// call such as Wrap is made in the same function that produced the error
+// being wrapped.
+func (s StackTrace) Merge(other StackTrace) StackTrace {
+	maxOverlap := len(s)
+	if len(other) < maxOverlap {
+		maxOverlap = len(other)
 	}
-	for i, frame := range ss {
-		appended[i+len(s)] = frame
+
+	overlap := 0
+	for k := maxOverlap; k > 0; k-- {
+		if s[len(s)-k:].equalFrames(other[:k]) {
+			overlap = k
+			break
+		}
+	}
+
+	merged := make(StackTrace, 0, len(s)-overlap+len(other))
+	merged = append(merged, s[:len(s)-overlap]...)
+	merged = append(merged, other...)
+
+	return merged
+}
+
+// equalFrames reports whether s and other hold the same sequence of call
+// sites, as judged by Frame.equal. It is only ever called with two slices
+// of the same length.
+func (s StackTrace) equalFrames(other StackTrace) bool {
+	for i, f := range s {
+		if !f.equal(other[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func appendStackTrace(s, ss StackTrace) StackTrace {
+	return s.Merge(ss)
+}
+
+// MarshalJSON marshals the StackTrace into a JSON array of its Frames,
+// using [] rather than null for an empty trace. It marshals the same
+// expanded Frames that Format and %+v see.
+func (s StackTrace) MarshalJSON() ([]byte, error) {
+	frames := s.Frames()
+	if len(frames) == 0 {
+		return []byte("[]"), nil
 	}
 
-	return appended
+	return json.Marshal(frames)
 }
 
 // Frame represents a single frame in a stack trace.
 type Frame struct {
 	pc uintptr
+
+	// expanded, when true, means funcName, file, and line were already
+	// resolved by StackTrace.Frames (which correctly accounts for inlined
+	// calls), and parts should use them as-is instead of resolving pc
+	// again with runtime.FuncForPC.
+	expanded bool
+	funcName string
+	file     string
+	line     int
 }
 
-// String returns a formatted string representation of the Frame.
-// It includes the function name, file path (relative to the working directory), and line number.
-func (f Frame) String() string {
-	file := "unknown"
-	funcName := "unknown"
-	var line int
-	fn := runtime.FuncForPC(f.pc)
+// frameExpansions memoizes Frame.expand's runtime.CallersFrames results,
+// keyed by the raw pc, since a given pc always expands to the same
+// Frame(s) and the same call site's pc is typically shared across many
+// error values. A sync.Map, rather than a field on Frame, is what makes
+// this safe to populate from concurrent calls to StackTrace.Frames (e.g.
+// formatting and marshaling the same error from two goroutines at once).
+var frameExpansions sync.Map // map[uintptr][]Frame
+
+// expand resolves f's pc into its Frame(s) with runtime.CallersFrames,
+// which, unlike Frame.parts' fallback to runtime.FuncForPC, accounts for
+// inlining and so may return more than one Frame for a single pc. The
+// result is memoized in frameExpansions; a second call for the same pc
+// returns the cached slice instead of resolving it again. If f is already
+// expanded, it is its own result.
+func (f Frame) expand() []Frame {
+	if f.expanded {
+		return []Frame{f}
+	}
+	if cached, ok := frameExpansions.Load(f.pc); ok {
+		return cached.([]Frame)
+	}
+
+	var resolved []Frame
+	iter := runtime.CallersFrames([]uintptr{f.pc})
+	for {
+		rf, more := iter.Next()
+		resolved = append(resolved, Frame{
+			pc:       rf.PC,
+			expanded: true,
+			funcName: rf.Function,
+			file:     strings.TrimPrefix(rf.File, workingDir+"/"),
+			line:     rf.Line,
+		})
+		if !more {
+			break
+		}
+	}
+
+	actual, _ := frameExpansions.LoadOrStore(f.pc, resolved)
+	return actual.([]Frame)
+}
+
+// equal reports whether f and other represent the same call site, either
+// because they share a program counter or because they resolve to the same
+// function, file, and line.
+func (f Frame) equal(other Frame) bool {
+	if f.pc == other.pc {
+		return true
+	}
+
+	fn, otherFn := runtime.FuncForPC(f.pc-1), runtime.FuncForPC(other.pc-1)
+	if fn == nil || otherFn == nil {
+		return false
+	}
+	file, line := fn.FileLine(f.pc - 1)
+	otherFile, otherLine := otherFn.FileLine(other.pc - 1)
+
+	return fn.Name() == otherFn.Name() && file == otherFile && line == otherLine
+}
+
+// parts resolves the Frame's function name, file path (relative to the
+// working directory), and line number. If f was already resolved by
+// StackTrace.Frames, it returns those values directly. Otherwise it falls
+// back to resolving f.pc with runtime.FuncForPC, which does not account
+// for inlining; if the pc cannot be resolved at all, it returns "unknown"
+// for the name and file. f.pc is a return address, so it is resolved at
+// f.pc-1, the convention FuncForPC expects for locating the calling line
+// rather than whatever follows the call.
+func (f Frame) parts() (funcName, file string, line int) {
+	if f.expanded {
+		return f.funcName, f.file, f.line
+	}
+
+	funcName, file = "unknown", "unknown"
+	fn := runtime.FuncForPC(f.pc - 1)
 	if fn != nil {
 		funcName = fn.Name()
-		file, line = fn.FileLine(f.pc)
+		file, line = fn.FileLine(f.pc - 1)
+		file = strings.TrimPrefix(file, workingDir+"/")
 	}
 
-	return fmt.Sprintf("%s(%s:%d)", funcName, strings.TrimPrefix(file, workingDir+"/"), line)
+	return funcName, file, line
+}
+
+// String returns a formatted string representation of the Frame.
+// It includes the function name, file path (relative to the working directory), and line number.
+func (f Frame) String() string {
+	funcName, file, line := f.parts()
+	return fmt.Sprintf("%s(%s:%d)", funcName, file, line)
+}
+
+// MarshalJSON marshals the Frame into a {"func", "file", "line"} object,
+// so a stack trace serializes into a stable schema for JSON logging pipelines.
+func (f Frame) MarshalJSON() ([]byte, error) {
+	funcName, file, line := f.parts()
+	return json.Marshal(struct {
+		Func string `json:"func"`
+		File string `json:"file"`
+		Line int    `json:"line"`
+	}{Func: funcName, File: file, Line: line})
+}
+
+// MarshalText marshals the Frame into the same text produced by String, so
+// it can be used as a map key or with encoders that prefer text over JSON objects.
+func (f Frame) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
 }
 
 // Format formats the Frame according to the fmt.State and verb.
@@ -76,8 +272,13 @@ func (f Frame) Format(state fmt.State, verb rune) {
 	}
 }
 
-// caller returns a new StackTrace starting from the specified number of frames to skip.
+// caller returns a new StackTrace holding the raw program counter of the
+// frame the specified number of frames up the stack. It uses
+// runtime.Callers rather than runtime.Caller so that, once resolved via
+// StackTrace.Frames, a call site the compiler inlined expands into every
+// inlined call instead of just the outermost one.
 func caller(skip int) StackTrace {
-	pc, _, _, _ := runtime.Caller(skip)
-	return []Frame{{pc: pc}}
+	var pcs [1]uintptr
+	runtime.Callers(skip+1, pcs[:])
+	return StackTrace{{pc: pcs[0]}}
 }
@@ -1,10 +1,12 @@
 package errors
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"runtime"
 	"strconv"
+	"sync"
 	"testing"
 )
 
@@ -24,11 +26,11 @@ func (x *X) ptr() Frame {
 
 func TestFrame_String(t *testing.T) {
 	s := frame()
-	assert.Equal(t, "github.com/psi59/errors.TestFrame_String(stack_test.go:26)", s.String())
-	assert.Equal(t, "github.com/psi59/errors.init(stack_test.go:11)", testFrame.String())
+	assert.Equal(t, "github.com/psi59/errors.TestFrame_String(stack_test.go:28)", s.String())
+	assert.Equal(t, "github.com/psi59/errors.init(stack_test.go:13)", testFrame.String())
 	var x X
-	assert.Equal(t, "github.com/psi59/errors.X.val(stack_test.go:17)", x.val().String())
-	assert.Equal(t, "github.com/psi59/errors.(*X).ptr(stack_test.go:22)", x.ptr().String())
+	assert.Equal(t, "github.com/psi59/errors.X.val(stack_test.go:19)", x.val().String())
+	assert.Equal(t, "github.com/psi59/errors.(*X).ptr(stack_test.go:24)", x.ptr().String())
 }
 
 func TestFrame_Format(t *testing.T) {
@@ -41,12 +43,12 @@ func TestFrame_Format(t *testing.T) {
 		{
 			frame:  x.ptr(),
 			format: "%v",
-			want:   "github.com/psi59/errors.(*X).ptr(stack_test.go:22)",
+			want:   "github.com/psi59/errors.(*X).ptr(stack_test.go:24)",
 		},
 		{
 			frame:  x.ptr(),
 			format: "%+v",
-			want:   "\n\tat github.com/psi59/errors.(*X).ptr(stack_test.go:22)",
+			want:   "\n\tat github.com/psi59/errors.(*X).ptr(stack_test.go:24)",
 		},
 	}
 	for _, tt := range tests {
@@ -58,8 +60,108 @@ func TestFrame_Format(t *testing.T) {
 }
 
 func frame() Frame {
-	pc, _, _, _ := runtime.Caller(1)
-	return Frame{pc: pc}
+	var pcs [1]uintptr
+	runtime.Callers(2, pcs[:])
+	return Frame{pc: pcs[0]}
+}
+
+func TestStackTrace_Frames(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		assert.Nil(t, StackTrace(nil).Frames())
+	})
+
+	t.Run("resolves the real call site", func(t *testing.T) {
+		s := caller(1)
+		frames := s.Frames()
+		assert.Len(t, frames, 1)
+		assert.Contains(t, frames[0].String(), "TestStackTrace_Frames")
+	})
+
+	t.Run("repeated calls return the same expansion", func(t *testing.T) {
+		s := caller(1)
+		first := s.Frames()
+		second := s.Frames()
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("concurrent calls on the same StackTrace do not race", func(t *testing.T) {
+		s := caller(1)
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.Frames()
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func TestMaxStackDepth(t *testing.T) {
+	orig := MaxStackDepth
+	defer func() { MaxStackDepth = orig }()
+
+	MaxStackDepth = 0
+	assert.Equal(t, hardStackDepthCap, maxStackDepth())
+
+	MaxStackDepth = hardStackDepthCap + 1
+	assert.Equal(t, hardStackDepthCap, maxStackDepth())
+
+	MaxStackDepth = 5
+	assert.Equal(t, 5, maxStackDepth())
+}
+
+func TestFrame_MarshalJSON(t *testing.T) {
+	var x X
+	got, err := json.Marshal(x.val())
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"func":"github.com/psi59/errors.X.val","file":"stack_test.go","line":19}`, string(got))
+}
+
+func TestFrame_MarshalText(t *testing.T) {
+	var x X
+	got, err := x.val().MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, x.val().String(), string(got))
+}
+
+func TestStackTrace_MarshalJSON(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		got, err := json.Marshal(StackTrace(nil))
+		assert.NoError(t, err)
+		assert.Equal(t, "[]", string(got))
+	})
+
+	t.Run("OK", func(t *testing.T) {
+		var x X
+		got, err := json.Marshal(StackTrace{x.val()})
+		assert.NoError(t, err)
+		assert.JSONEq(t, `[{"func":"github.com/psi59/errors.X.val","file":"stack_test.go","line":19}]`, string(got))
+	})
+}
+
+func TestStackTrace_Merge(t *testing.T) {
+	var x X
+	t.Run("no overlap", func(t *testing.T) {
+		s := StackTrace{x.ptr()}
+		other := StackTrace{x.val()}
+		assert.Equal(t, StackTrace{s[0], other[0]}, s.Merge(other))
+	})
+
+	t.Run("overlapping head is dropped", func(t *testing.T) {
+		f := frame()
+		s := StackTrace{f}
+		other := StackTrace{f}
+		assert.Equal(t, StackTrace{f}, s.Merge(other))
+	})
+
+	t.Run("multi-frame overlap is collapsed, not just the last frame", func(t *testing.T) {
+		a, b, c := x.ptr(), x.val(), testFrame
+		s := StackTrace{a, b}
+		other := StackTrace{b, c}
+		assert.Equal(t, StackTrace{a, b, c}, s.Merge(other))
+	})
 }
 
 func TestStackTrace_Format(t *testing.T) {
@@ -83,8 +185,8 @@ func TestStackTrace_Format(t *testing.T) {
 				x.val(),
 			},
 			format: "%+v",
-			want: "\n\tat github.com/psi59/errors.(*X).ptr(stack_test.go:22)" +
-				"\n\tat github.com/psi59/errors.X.val(stack_test.go:17)",
+			want: "\n\tat github.com/psi59/errors.(*X).ptr(stack_test.go:24)" +
+				"\n\tat github.com/psi59/errors.X.val(stack_test.go:19)",
 		},
 		{
 			stack: StackTrace{
@@ -92,8 +194,8 @@ func TestStackTrace_Format(t *testing.T) {
 				testFrame,
 			},
 			format: "%+v",
-			want: "\n\tat github.com/psi59/errors.(*X).ptr(stack_test.go:22)" +
-				"\n\tat github.com/psi59/errors.init(stack_test.go:11)",
+			want: "\n\tat github.com/psi59/errors.(*X).ptr(stack_test.go:24)" +
+				"\n\tat github.com/psi59/errors.init(stack_test.go:13)",
 		},
 	}
 	for i, tt := range tests {